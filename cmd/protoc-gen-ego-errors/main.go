@@ -0,0 +1,117 @@
+// Command protoc-gen-ego-errors is a protoc plugin that reads a proto enum
+// annotated with (ego.errors.default_code) and emits one Go constructor per
+// enum value, each returning a pre-registered *eerrors.EgoError whose
+// Reason is the enum value's name. It removes the boilerplate of hand
+// writing eerrors.New + eerrors.Register for every domain error.
+//
+// Usage (with protoc or buf):
+//
+//	protoc --go_out=. --ego-errors_out=. --ego-errors_opt=paths=source_relative errors.proto
+//
+// `ego gen errors` is expected to shell out to this binary the same way
+// `ego gen pb` shells out to protoc-gen-go; that subcommand isn't wired up
+// here since this checkout doesn't carry the `ego` CLI tree.
+//
+// The generated constructors take a printf-style format string, the way
+// kratos' errors plugin does (e.g. ErrUserError_NOT_FOUND("user %d", id)).
+// What's still missing: the request also asked for typed WithMetadata
+// helpers derived from field options, but errors.proto declares no such
+// field-option extension to derive them from, so that half isn't
+// implemented here.
+package main
+
+import (
+	"flag"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+
+	"github.com/gotomicro/ego/core/eerrors/errorspb"
+)
+
+func main() {
+	var flags flag.FlagSet
+	protogen.Options{ParamFunc: flags.Set}.Run(func(gen *protogen.Plugin) error {
+		gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+		for _, f := range gen.Files {
+			if !f.Generate {
+				continue
+			}
+			generateFile(gen, f)
+		}
+		return nil
+	})
+}
+
+func generateFile(gen *protogen.Plugin, file *protogen.File) {
+	target := false
+	for _, enum := range file.Enums {
+		if _, ok := defaultCode(enum); ok {
+			target = true
+			break
+		}
+	}
+	if !target {
+		return
+	}
+
+	g := gen.NewGeneratedFile(file.GeneratedFilenamePrefix+"_errors.pb.go", file.GoImportPath)
+	g.P("// Code generated by protoc-gen-ego-errors. DO NOT EDIT.")
+	g.P("// source: ", file.Desc.Path())
+	g.P()
+	g.P("package ", file.GoPackageName)
+	g.P()
+
+	fmtPkg := protogen.GoImportPath("fmt")
+	eerrorsPkg := protogen.GoImportPath("github.com/gotomicro/ego/core/eerrors")
+	for _, enum := range file.Enums {
+		code, ok := defaultCode(enum)
+		if !ok {
+			continue
+		}
+		for _, v := range enum.Values {
+			reason := string(v.Desc.Name())
+			// registered is unexported and derived from, but distinct from,
+			// v.GoIdent.GoName: protoc-gen-go emits an exported Go constant
+			// with that exact identifier for the same enum value in this
+			// same package, so reusing it here verbatim would redeclare it.
+			registered := unexported(v.GoIdent.GoName)
+			ctor := "Err" + v.GoIdent.GoName
+
+			g.P("var ", registered, " = ", g.QualifiedGoIdent(eerrorsPkg.Ident("Register")), "(",
+				g.QualifiedGoIdent(eerrorsPkg.Ident("New")), "(", code, ", \"", reason, "\", \"\"))")
+			g.P()
+			g.P("// ", ctor, " builds a ", g.QualifiedGoIdent(eerrorsPkg.Ident("EgoError")),
+				" for enum value ", reason, ", formatting its message like fmt.Sprintf.")
+			g.P("func ", ctor, "(format string, args ...interface{}) *", g.QualifiedGoIdent(eerrorsPkg.Ident("EgoError")), " {")
+			g.P("return ", registered, ".WithMsg(", g.QualifiedGoIdent(fmtPkg.Ident("Sprintf")), "(format, args...)).(*",
+				g.QualifiedGoIdent(eerrorsPkg.Ident("EgoError")), ")")
+			g.P("}")
+			g.P()
+		}
+	}
+}
+
+// unexported lowercases the leading rune of ident so the result can never
+// collide with an exported identifier of the same spelling.
+func unexported(ident string) string {
+	r, size := utf8.DecodeRuneInString(ident)
+	if r == utf8.RuneError {
+		return ident
+	}
+	return string(unicode.ToLower(r)) + ident[size:]
+}
+
+// defaultCode reads the (ego.errors.default_code) option off enum, if set.
+func defaultCode(enum *protogen.Enum) (int32, bool) {
+	opts, ok := enum.Desc.Options().(*descriptorpb.EnumOptions)
+	if !ok || opts == nil || !proto.HasExtension(opts, errorspb.E_DefaultCode) {
+		return 0, false
+	}
+	code, ok := proto.GetExtension(opts, errorspb.E_DefaultCode).(int32)
+	return code, ok
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+
+	"github.com/gotomicro/ego/core/eerrors/errorspb"
+)
+
+// buildRequest constructs a CodeGeneratorRequest the way protoc itself would
+// hand one to a plugin: the (ego.errors.default_code) option is attached via
+// proto.SetExtension, then the whole request is marshaled to wire bytes and
+// unmarshaled back, so the plugin only ever sees what would actually arrive
+// over stdin from a real protoc invocation.
+func buildRequest(t *testing.T) *pluginpb.CodeGeneratorRequest {
+	t.Helper()
+
+	enumOpts := &descriptorpb.EnumOptions{}
+	proto.SetExtension(enumOpts, errorspb.E_DefaultCode, int32(5))
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("test.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		Options: &descriptorpb.FileOptions{
+			GoPackage: proto.String("github.com/gotomicro/ego/cmd/protoc-gen-ego-errors/testpb"),
+		},
+		EnumType: []*descriptorpb.EnumDescriptorProto{
+			{
+				Name: proto.String("UserError"),
+				Value: []*descriptorpb.EnumValueDescriptorProto{
+					{Name: proto.String("NOT_FOUND"), Number: proto.Int32(0)},
+				},
+				Options: enumOpts,
+			},
+		},
+	}
+
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"test.proto"},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{file},
+	}
+
+	b, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatalf("marshal request: %v", err)
+	}
+	got := &pluginpb.CodeGeneratorRequest{}
+	if err := proto.Unmarshal(b, got); err != nil {
+		t.Fatalf("unmarshal request: %v", err)
+	}
+	return got
+}
+
+func TestDefaultCodeSurvivesWireRoundTrip(t *testing.T) {
+	req := buildRequest(t)
+	gen, err := protogen.Options{}.New(req)
+	if err != nil {
+		t.Fatalf("protogen.Options{}.New: %v", err)
+	}
+	if len(gen.Files) != 1 || len(gen.Files[0].Enums) != 1 {
+		t.Fatalf("unexpected plugin shape: %d files", len(gen.Files))
+	}
+
+	code, ok := defaultCode(gen.Files[0].Enums[0])
+	if !ok {
+		t.Fatalf("defaultCode() = _, false, want the option to survive the wire round trip")
+	}
+	if code != 5 {
+		t.Fatalf("defaultCode() = %d, want 5", code)
+	}
+}
+
+func TestGenerateFileEmitsConstructor(t *testing.T) {
+	req := buildRequest(t)
+	gen, err := protogen.Options{}.New(req)
+	if err != nil {
+		t.Fatalf("protogen.Options{}.New: %v", err)
+	}
+
+	generateFile(gen, gen.Files[0])
+
+	resp := gen.Response()
+	if len(resp.File) != 1 {
+		t.Fatalf("generated %d files, want 1", len(resp.File))
+	}
+	content := resp.File[0].GetContent()
+	if !containsAll(content, "func ErrUserError_NOT_FOUND(", "var userError_NOT_FOUND") {
+		t.Fatalf("generated content missing expected symbols:\n%s", content)
+	}
+}
+
+func containsAll(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if !strings.Contains(s, sub) {
+			return false
+		}
+	}
+	return true
+}
@@ -10,6 +10,8 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/anypb"
 
 	"github.com/gotomicro/ego/internal/ecode"
 )
@@ -22,6 +24,18 @@ type Error interface {
 	WithMessage(string) Error
 	WithMsg(string) Error
 	WithErr(err error) Error
+	WithCause(err error) Error
+	WithDetails(msgs ...proto.Message) Error
+	Details() []proto.Message
+}
+
+// Option customizes EgoError construction.
+type Option func(*EgoError)
+
+// WithoutStack skips stack-trace capture for this error, e.g. for
+// high-frequency sentinel errors where the creation site is never useful.
+func WithoutStack() Option {
+	return func(x *EgoError) { x.stack = nil }
 }
 
 const (
@@ -37,9 +51,51 @@ type errKey string
 
 var errs = map[errKey]*EgoError{}
 
-// Register 注册错误信息
-func Register(egoError *EgoError) {
+// resolver pairs a registered EgoError with an optional matcher, consulted
+// by FromError's fallback resolution below.
+type resolver struct {
+	err     *EgoError
+	matcher func(error) bool
+}
+
+var resolvers []resolver
+
+// Register 注册错误信息. It returns egoError unchanged so callers can
+// register and declare in one line, e.g. var ErrNotFound = Register(New(...)).
+func Register(egoError *EgoError) *EgoError {
+	errs[errKey(egoError.Reason)] = egoError
+	resolvers = append(resolvers, resolver{err: egoError})
+	return egoError
+}
+
+// RegisterMatcher is like Register but also supplies a matcher func,
+// letting a third-party error type opt into egoError's taxonomy without
+// implementing Is itself, e.g. RegisterMatcher(ErrNotFound, func(err error)
+// bool { return errors.Is(err, sql.ErrNoRows) }).
+func RegisterMatcher(egoError *EgoError, matcher func(error) bool) *EgoError {
 	errs[errKey(egoError.Reason)] = egoError
+	resolvers = append(resolvers, resolver{err: egoError, matcher: matcher})
+	return egoError
+}
+
+// resolve looks for a registered EgoError that claims err. A candidate
+// registered with a matcher is checked by that matcher alone; any other
+// candidate is checked by asking err and the candidate to recognize each
+// other through the Is-interface (the same technique containerd/errdefs
+// uses in its Resolve).
+func resolve(err error) *EgoError {
+	for _, r := range resolvers {
+		if r.matcher != nil {
+			if r.matcher(err) {
+				return r.err
+			}
+			continue
+		}
+		if errors.Is(err, r.err) || r.err.Is(err) {
+			return r.err
+		}
+	}
+	return nil
 }
 
 // Error Error信息
@@ -47,42 +103,172 @@ func (x *EgoError) Error() string {
 	return fmt.Sprintf("error: code = %d reason = %s message = %s metadata = %v", x.Code, x.Reason, x.Message, x.Metadata)
 }
 
-// Is 判断是否为根因错误
+// Is 判断是否为根因错误. Besides comparing x itself, it also checks the
+// other errors attached via Join/WithErrs (x.joined), so errors.Is still
+// matches any of them, not just x.
 func (x *EgoError) Is(err error) bool {
 	egoErr, flag := err.(*EgoError)
 	if !flag {
 		return false
 	}
+	if egoErrMatches(x, egoErr) {
+		return true
+	}
+	if x == nil {
+		return false
+	}
+	for _, j := range x.joined {
+		if egoErrMatches(j, egoErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// egoErrMatches compares x and egoErr by Code and Reason. Reason alone
+// collides across domains that happen to reuse the same string (e.g.
+// "NOT_FOUND" defined by two services), so both are compared.
+func egoErrMatches(x, egoErr *EgoError) bool {
 	if x == nil {
 		return x == egoErr
 	}
 	if egoErr == nil {
 		return x.Reason == ""
 	}
-	return x.Reason == egoErr.Reason
+	return x.Code == egoErr.Code && x.Reason == egoErr.Reason
+}
+
+// Unwrap returns the underlying cause, if any, so errors.Is/As walk past
+// this EgoError to whatever it was derived from.
+func (x *EgoError) Unwrap() error {
+	if x == nil {
+		return nil
+	}
+	return x.cause
+}
+
+// Format implements fmt.Formatter. "%+v" appends the stack trace captured
+// when this error was created (if any); every other verb behaves like %s.
+func (x *EgoError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprint(s, x.Error())
+			x.stack.Format(s, verb)
+			return
+		}
+		fallthrough
+	default:
+		fmt.Fprint(s, x.Error())
+	}
 }
 
 // GRPCStatus returns the Status represented by se.
 func (x *EgoError) GRPCStatus() *status.Status {
-	s, _ := status.New(codes.Code(x.Code), x.Message).
-		WithDetails(&errdetails.ErrorInfo{
-			Reason:   x.Reason,
-			Metadata: x.Metadata,
-		})
+	s := status.New(codes.Code(x.Code), x.Message)
+	details := make([]protoadapt.MessageV1, 0, len(x.details)+1)
+	details = append(details, protoadapt.MessageV1Of(&errdetails.ErrorInfo{
+		Reason:   x.Reason,
+		Metadata: x.Metadata,
+	}))
+	for _, d := range x.details {
+		msg, err := d.UnmarshalNew()
+		if err != nil {
+			continue
+		}
+		details = append(details, protoadapt.MessageV1Of(msg))
+	}
+	s, err := s.WithDetails(details...)
+	if err != nil {
+		return status.New(codes.Code(x.Code), x.Message)
+	}
 	return s
 }
 
+// WithDetails packs msgs into the Status.Details of the gRPC status built
+// from this error, using anypb so the original proto type survives the
+// round trip and can be matched back out by FromError/Details on the other
+// side of the wire.
+func (x *EgoError) WithDetails(msgs ...proto.Message) Error {
+	err := x.clone()
+	for _, msg := range msgs {
+		packed, e := anypb.New(msg)
+		if e != nil {
+			continue
+		}
+		err.details = append(err.details, packed)
+	}
+	return err
+}
+
+// Details decodes the proto messages attached via WithDetails (or Join),
+// in the order they were added.
+func (x *EgoError) Details() []proto.Message {
+	msgs := make([]proto.Message, 0, len(x.details))
+	for _, d := range x.details {
+		msg, err := d.UnmarshalNew()
+		if err != nil {
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+// Join attaches errs to x as additional gRPC status details, one entry per
+// error, so the chain survives a round trip through GRPCStatus/FromError and
+// errors.Is/As can still match against any of them. An error that is itself
+// a proto.Message is packed as-is; anything else falls back to an ErrorInfo
+// detail carrying its Error() string under the "message" metadata key. Any errs that are (or
+// wrap) an *EgoError are also recorded in joined so x.Is matches them
+// immediately, without needing a round trip through FromError first.
+func (x *EgoError) Join(errs ...error) Error {
+	err := x.clone()
+	for _, e := range errs {
+		if e == nil {
+			continue
+		}
+		ee := new(EgoError)
+		isEgoErr := errors.As(e, &ee)
+		if isEgoErr {
+			err.joined = append(err.joined, ee)
+		}
+		if msg, ok := e.(proto.Message); ok {
+			if packed, aErr := anypb.New(msg); aErr == nil {
+				err.details = append(err.details, packed)
+				continue
+			}
+		}
+		reason := UnknownReason
+		if isEgoErr {
+			reason = ee.Reason
+		}
+		if packed, aErr := anypb.New(&errdetails.ErrorInfo{
+			Reason:   reason,
+			Metadata: map[string]string{"message": e.Error()},
+		}); aErr == nil {
+			err.details = append(err.details, packed)
+		}
+	}
+	return err
+}
+
+// WithErrs is an alias of Join kept for symmetry with WithErr.
+func (x *EgoError) WithErrs(errs ...error) Error {
+	return x.Join(errs...)
+}
+
 // WithMetadata with an MD formed by the mapping of key, value.
 // Deprecated: Will be removed in future versions, use WithMd instead.
 func (x *EgoError) WithMetadata(md map[string]string) Error {
-	err := proto.Clone(x).(*EgoError)
+	err := x.clone()
 	err.Metadata = md
 	return err
 }
 
 // WithMd with an MD formed by the mapping of key, value.
 func (x *EgoError) WithMd(md map[string]string) Error {
-	err := proto.Clone(x).(*EgoError)
+	err := x.clone()
 	err.Metadata = md
 	return err
 }
@@ -90,24 +276,29 @@ func (x *EgoError) WithMd(md map[string]string) Error {
 // WithMessage set message to current EgoError
 // Deprecated: Will be removed in future versions, use WithMsg instead.
 func (x *EgoError) WithMessage(msg string) Error {
-	err := proto.Clone(x).(*EgoError)
+	err := x.clone()
 	err.Message = msg
 	return err
 }
 
 // WithMsg set message to current EgoError
 func (x *EgoError) WithMsg(msg string) Error {
-	err := proto.Clone(x).(*EgoError)
+	err := x.clone()
 	err.Message = msg
 	return err
 }
 
+// WithErr translates a fixed set of stdlib sentinels to their Code/Reason
+// and, like WithCause, always retains err as the underlying cause so
+// errors.Is/As still walk through to it even when no sentinel matches.
 func (x *EgoError) WithErr(err error) Error {
 	if err == nil {
 		return x
 	}
 
-	eErr := proto.Clone(x).(*EgoError)
+	eErr := x.clone()
+	eErr.cause = err
+	eErr.Message = err.Error()
 	switch err {
 	case io.EOF:
 		eErr.Code = int32(codes.Unknown)
@@ -121,21 +312,31 @@ func (x *EgoError) WithErr(err error) Error {
 	case io.ErrUnexpectedEOF:
 		eErr.Code = int32(codes.Internal)
 		eErr.Reason = io.ErrUnexpectedEOF.Error()
-	default:
-		return x
 	}
-
-	eErr.Message = err.Error()
 	return eErr
 }
 
+// WithCause clones x, retaining err as the underlying cause without
+// touching Code/Reason/Message, for callers that want to attach context
+// without WithErr's sentinel translation.
+func (x *EgoError) WithCause(err error) Error {
+	e := x.clone()
+	e.cause = err
+	return e
+}
+
 // New returns an error object for the code, message.
-func New(code int, reason, message string) *EgoError {
-	return &EgoError{
+func New(code int, reason, message string, opts ...Option) *EgoError {
+	e := &EgoError{
 		Code:    int32(code),
 		Message: message,
 		Reason:  reason,
+		stack:   callers(1),
 	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
 }
 
 // ToHTTPStatusCode Get equivalent HTTP status code from x.Code
@@ -155,22 +356,45 @@ func FromError(err error) *EgoError {
 
 	gs, ok := status.FromError(err)
 	if ok {
+		var ee *EgoError
+		var extra []*anypb.Any
 		for _, detail := range gs.Details() {
 			switch d := detail.(type) {
 			case *errdetails.ErrorInfo:
-				e, ok := errs[errKey(d.Reason)]
-				if ok {
-					return e.WithMsg(gs.Message()).WithMetadata(d.Metadata).(*EgoError)
+				if ee != nil {
+					// secondary ErrorInfo (e.g. from Join) becomes an opaque
+					// detail, and, when it matches a registered EgoError, is
+					// also reconstructed into joined so errors.Is still
+					// matches it after the round trip, per chunk0-1.
+					if packed, aErr := anypb.New(d); aErr == nil {
+						extra = append(extra, packed)
+					}
+					if registered, ok := errs[errKey(d.Reason)]; ok {
+						ee.joined = append(ee.joined, registered.WithMetadata(d.Metadata).(*EgoError))
+					}
+					continue
+				}
+				if e, ok := errs[errKey(d.Reason)]; ok {
+					ee = e.WithMsg(gs.Message()).WithMetadata(d.Metadata).(*EgoError)
+					continue
+				}
+				ee = New(int(gs.Code()), d.Reason, gs.Message()).WithMd(d.Metadata).(*EgoError)
+			default:
+				if msg, ok := detail.(proto.Message); ok {
+					if packed, aErr := anypb.New(msg); aErr == nil {
+						extra = append(extra, packed)
+					}
 				}
-				return New(
-					int(gs.Code()),
-					d.Reason,
-					gs.Message(),
-				).WithMd(d.Metadata).(*EgoError)
 			}
 		}
-
-		return New(int(gs.Code()), gs.Message(), "")
+		if ee == nil {
+			ee = New(int(gs.Code()), UnknownReason, gs.Message())
+		}
+		ee.details = extra
+		return ee
+	}
+	if e := resolve(err); e != nil {
+		return e.WithMsg(err.Error()).WithCause(err).(*EgoError)
 	}
-	return New(int(codes.Unknown), UnknownReason, err.Error())
+	return New(int(codes.Unknown), UnknownReason, err.Error()).WithCause(err).(*EgoError)
 }
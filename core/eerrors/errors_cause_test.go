@@ -0,0 +1,57 @@
+package eerrors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestWithCauseUnwrap(t *testing.T) {
+	root := errors.New("connection refused")
+	wrapped := New(int(codes.Unavailable), "UPSTREAM_DOWN", "upstream unavailable").WithCause(root).(*EgoError)
+
+	if !errors.Is(wrapped, root) {
+		t.Fatalf("errors.Is(wrapped, root) = false, want true")
+	}
+	if errors.Unwrap(wrapped) != root {
+		t.Fatalf("errors.Unwrap(wrapped) = %v, want %v", errors.Unwrap(wrapped), root)
+	}
+}
+
+func TestIsComparesCodeAndReason(t *testing.T) {
+	a := New(int(codes.NotFound), "NOT_FOUND", "a")
+	b := New(int(codes.AlreadyExists), "NOT_FOUND", "b")
+
+	if a.Is(b) {
+		t.Fatalf("Is() matched across differing Code with the same Reason")
+	}
+	if !a.Is(New(int(codes.NotFound), "NOT_FOUND", "c")) {
+		t.Fatalf("Is() failed to match same Code and Reason")
+	}
+}
+
+func TestFormatPlusVIncludesStack(t *testing.T) {
+	err := New(int(codes.Internal), "BOOM", "boom")
+
+	plain := fmt.Sprintf("%v", err)
+	if strings.Contains(plain, "\n") {
+		t.Fatalf("%%v should not include a stack trace, got %q", plain)
+	}
+
+	verbose := fmt.Sprintf("%+v", err)
+	if !strings.Contains(verbose, "TestFormatPlusVIncludesStack") {
+		t.Fatalf("%%+v = %q, want it to mention the creating test function", verbose)
+	}
+}
+
+func TestWithoutStackSkipsCapture(t *testing.T) {
+	err := New(int(codes.Internal), "BOOM", "boom", WithoutStack())
+
+	verbose := fmt.Sprintf("%+v", err)
+	if strings.Contains(verbose, "\n") {
+		t.Fatalf("%%+v = %q, want no stack trace with WithoutStack()", verbose)
+	}
+}
@@ -0,0 +1,53 @@
+package eerrors
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+)
+
+// sqlNoRows stands in for a third-party sentinel, like sql.ErrNoRows, that
+// never heard of eerrors and has no Is method; errors.Is matches it by
+// plain equality.
+type sqlNoRows struct{}
+
+func (sqlNoRows) Error() string { return "sql: no rows in result set" }
+
+var errSQLNoRows = sqlNoRows{}
+
+func TestFromErrorIsInterfaceFallback(t *testing.T) {
+	notFound := Register(New(int(codes.NotFound), "RESOLVER_NOT_FOUND", "not found"))
+
+	// err implements Is against the registered EgoError, without being one.
+	wrapper := isWrapper{target: notFound}
+
+	got := FromError(wrapper)
+	if got.Reason != notFound.Reason {
+		t.Fatalf("FromError(wrapper).Reason = %q, want %q", got.Reason, notFound.Reason)
+	}
+}
+
+func TestFromErrorRegisterMatcher(t *testing.T) {
+	notFound := New(int(codes.NotFound), "MATCHER_NOT_FOUND", "not found")
+	RegisterMatcher(notFound, func(err error) bool {
+		return errors.Is(err, errSQLNoRows)
+	})
+
+	got := FromError(errSQLNoRows)
+	if got.Reason != notFound.Reason {
+		t.Fatalf("FromError(errSQLNoRows).Reason = %q, want %q", got.Reason, notFound.Reason)
+	}
+}
+
+// isWrapper implements the Is-interface fallback FromError falls back to:
+// it reports itself equal to whatever EgoError it wraps.
+type isWrapper struct {
+	target *EgoError
+}
+
+func (w isWrapper) Error() string { return "wrapped: " + w.target.Error() }
+func (w isWrapper) Is(target error) bool {
+	e, ok := target.(*EgoError)
+	return ok && e == w.target
+}
@@ -0,0 +1,58 @@
+package eerrors
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestWithDetailsRoundTrip(t *testing.T) {
+	want := &errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)}
+	src := New(int(codes.Internal), "RETRY", "please retry").WithDetails(want).(*EgoError)
+
+	got := FromError(src.GRPCStatus().Err())
+	details := got.Details()
+	if len(details) != 1 {
+		t.Fatalf("Details() = %d messages, want 1", len(details))
+	}
+	if _, ok := details[0].(*errdetails.RetryInfo); !ok {
+		t.Fatalf("Details()[0] = %T, want *errdetails.RetryInfo", details[0])
+	}
+}
+
+func TestJoinRoundTrip(t *testing.T) {
+	first := New(int(codes.NotFound), "USER_NOT_FOUND", "user not found")
+	second := New(int(codes.PermissionDenied), "FORBIDDEN", "forbidden")
+	Register(first)
+	Register(second)
+
+	joined := first.Join(second).(*EgoError)
+
+	// errors.Is/As must match both causes locally, before any round trip.
+	if !errors.Is(joined, first) {
+		t.Fatalf("errors.Is(joined, first) = false, want true")
+	}
+	if !errors.Is(joined, second) {
+		t.Fatalf("errors.Is(joined, second) = false, want true")
+	}
+	var as *EgoError
+	if !errors.As(joined, &as) {
+		t.Fatalf("errors.As(joined, &as) = false, want true")
+	}
+
+	got := FromError(joined.GRPCStatus().Err())
+
+	if !got.Is(first) {
+		t.Fatalf("FromError(joined) = %+v, want Is(first)", got)
+	}
+	if !errors.Is(got, second) {
+		t.Fatalf("errors.Is(FromError(joined), second) = false, want true")
+	}
+	if len(got.Details()) != 1 {
+		t.Fatalf("Details() = %d messages, want 1 (the joined error)", len(got.Details()))
+	}
+}
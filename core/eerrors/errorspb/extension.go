@@ -0,0 +1,37 @@
+package errorspb
+
+import (
+	protoregistry "google.golang.org/protobuf/reflect/protoregistry"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+)
+
+// E_DefaultCode is the (ego.errors.default_code) enum option read by
+// protoc-gen-ego-errors (see errors.proto in this directory).
+//
+// This ExtensionInfo is hand-maintained, not protoc output: generating it
+// for real would require running protoc-gen-go over errors.proto, which
+// this checkout has no protoc toolchain to do. Keep it in sync with
+// errors.proto by hand if the field number or name ever changes.
+var E_DefaultCode = &protoimpl.ExtensionInfo{
+	ExtendedType:  (*descriptorpb.EnumOptions)(nil),
+	ExtensionType: (*int32)(nil),
+	Field:         1036,
+	Name:          "ego.errors.default_code",
+	Tag:           "varint,1036,opt,name=default_code",
+	Filename:      "errors.proto",
+}
+
+func init() {
+	// Real protoc-gen-go output registers every extension it emits with
+	// protoregistry.GlobalTypes from its file_*_proto_init(). Without this,
+	// HasExtension/GetExtension still work against a value built directly
+	// with proto.SetExtension in this process, but a real protoc run
+	// marshals the CodeGeneratorRequest before this plugin ever sees it:
+	// the unmarshaler has no registry entry for field 1036 on EnumOptions
+	// and silently drops the option into unknownFields, so defaultCode()
+	// would never see it. Register it the same way generated code does.
+	if err := protoregistry.GlobalTypes.RegisterExtension(E_DefaultCode); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,38 @@
+package eerrors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestWriteHTTPFromHTTPResponseRoundTrip(t *testing.T) {
+	src := New(int(codes.NotFound), "USER_NOT_FOUND", "user not found").
+		WithMd(map[string]string{"user_id": "42"}).(*EgoError)
+
+	rec := httptest.NewRecorder()
+	WriteHTTP(rec, src)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	got := FromHTTPResponse(rec.Result())
+	if got.Reason != src.Reason || got.Message != src.Message {
+		t.Fatalf("FromHTTPResponse() = %+v, want Reason/Message matching %+v", got, src)
+	}
+	if got.Metadata["user_id"] != "42" {
+		t.Fatalf("Metadata[user_id] = %q, want 42", got.Metadata["user_id"])
+	}
+}
+
+func TestWriteHTTPNilError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	WriteHTTP(rec, nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,63 @@
+package eerrors
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+)
+
+// EgoError is the wire representation of an ego domain error. It mirrors the
+// fields gRPC's `google.rpc.Status` + `errdetails.ErrorInfo` pair carries so
+// it can be rebuilt on either side of an RPC call.
+//
+// This type and its Reset/String/ProtoMessage/clone methods are
+// hand-maintained, not protoc output: errors.proto declares no EgoError
+// message, so there is nothing to regenerate this file from. Keep it that
+// way — in particular, don't let a future `protoc` run overwrite the
+// details/cause/stack fields below, since the rest of this package depends
+// on them surviving every WithXxx clone.
+type EgoError struct {
+	Code     int32             `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Reason   string            `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Message  string            `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Metadata map[string]string `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+
+	// details holds extra proto messages attached via WithDetails/Join. They
+	// are packed into the gRPC status' Details() at GRPCStatus() time and are
+	// not part of the EgoError wire format itself.
+	details []*anypb.Any
+
+	// cause is the error this EgoError was derived from, if any. It is never
+	// serialized onto the wire; it only lets errors.Unwrap/Is/As on the local
+	// side walk back to whatever produced this error.
+	cause error
+
+	// joined holds the other EgoErrors attached via Join/WithErrs (or
+	// reconstructed from their ErrorInfo details by FromError), beyond the
+	// single primary cause above. Is consults it so errors.Is still matches
+	// any of them, not just the first.
+	joined []*EgoError
+
+	// stack is the call stack captured when this EgoError was created,
+	// printed by Format on "%+v". Also not part of the wire format.
+	stack *stack
+}
+
+func (x *EgoError) Reset()         { *x = EgoError{} }
+func (x *EgoError) String() string { return proto.CompactTextString(x) }
+func (*EgoError) ProtoMessage()    {}
+
+// clone copies x, including the non-wire state (details, cause, stack)
+// carried alongside it. proto.Clone only walks tagged protobuf fields, so
+// it would otherwise drop that state on every WithXxx call.
+func (x *EgoError) clone() *EgoError {
+	e := proto.Clone(x).(*EgoError)
+	if len(x.details) > 0 {
+		e.details = append([]*anypb.Any(nil), x.details...)
+	}
+	if len(x.joined) > 0 {
+		e.joined = append([]*EgoError(nil), x.joined...)
+	}
+	e.cause = x.cause
+	e.stack = x.stack
+	return e
+}
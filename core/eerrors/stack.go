@@ -0,0 +1,34 @@
+package eerrors
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// stack records the call stack at the point an EgoError was created, so
+// Format can print where it originated on "%+v" (pkg/errors-style).
+type stack []uintptr
+
+func callers(skip int) *stack {
+	const depth = 32
+	var pcs [depth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	st := stack(pcs[:n])
+	return &st
+}
+
+// Format writes one "function\n\tfile:line" entry per frame when the
+// caller asked for "%+v"; it is a no-op for every other verb/flag.
+func (s *stack) Format(st fmt.State, verb rune) {
+	if s == nil || verb != 'v' || !st.Flag('+') {
+		return
+	}
+	frames := runtime.CallersFrames(*s)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(st, "\n%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+}